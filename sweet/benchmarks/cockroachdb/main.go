@@ -0,0 +1,628 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command cockroachdb-bench is the companion binary invoked by the
+// cockroachdb sweet harness (sweet/harnesses/cockroachdb.go). It starts a
+// cockroach cluster, drives it with a single `cockroach workload` (or, in
+// mixed-version mode, a rolling upgrade between two binaries), and reports
+// the result as a Go benchmark line so sweet can feed it to benchstat.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	bench        = flag.String("bench", "", "benchmark name to report results under")
+	workload     = flag.String("workload", "", "the `cockroach workload` generator to run, e.g. kv, tpcc, ycsb, tpch")
+	binPath      = flag.String("cockroachdb-bin", "", "path to the cockroach binary to run")
+	version      = flag.String("cockroachdb-version", "", "version tag the binary was built from, recorded alongside results")
+	nodes        = flag.Int("nodes", 1, "number of cockroach nodes to start")
+	concurrency  = flag.Int("concurrency", 0, "number of concurrent workload connections")
+	warmup       = flag.Duration("warmup", 0, "how long to run the workload before measuring")
+	duration     = flag.Duration("duration", 0, "how long to measure the workload for")
+	workloadInit = flag.Bool("workload-init", false, "run `cockroach workload init` before the measured run")
+	tmpDir       = flag.String("tmp", "", "scratch directory for node data")
+	short        = flag.Bool("short", false, "run an abbreviated version of the benchmark")
+
+	clusterKind  = flag.String("cluster", "local", "cluster backend to use: \"local\" or \"remote\"")
+	clusterHosts = flag.String("cluster-hosts", "", "comma-separated user@host SSH targets for the \"remote\" cluster backend")
+
+	binOld     = flag.String("cockroachdb-bin-old", "", "cockroach binary the cluster starts on, for the mixed-version upgrade benchmark")
+	binNew     = flag.String("cockroachdb-bin-new", "", "cockroach binary the cluster is upgraded to, for the mixed-version upgrade benchmark")
+	versionOld = flag.String("cockroachdb-version-old", "", "version tag of -cockroachdb-bin-old")
+	versionNew = flag.String("cockroachdb-version-new", "", "version tag of -cockroachdb-bin-new")
+)
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "cockroachdb-bench: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if *short {
+		*warmup = scaledForShort(*warmup)
+		*duration = scaledForShort(*duration)
+	}
+	if *binOld != "" || *binNew != "" {
+		return runMixedUpgrade()
+	}
+	return runWorkload()
+}
+
+// shortDurationDivisor is how much -short shrinks *warmup and *duration by,
+// so a -short run exercises the same code paths as a full run but finishes
+// quickly enough for a pre-submit smoke test.
+const shortDurationDivisor = 10
+
+func scaledForShort(d time.Duration) time.Duration {
+	return d / shortDurationDivisor
+}
+
+// runWorkload starts a cluster on *binPath and runs *workload against it,
+// reporting a single result line for *bench. flag.Args() (everything after
+// the "--" the harness inserts) is forwarded verbatim to both the init and
+// run steps, e.g. []string{"--warehouses", "10"}.
+func runWorkload() error {
+	bin := resolveBin(*binPath, *version)
+	c, err := startCluster(*clusterKind, *clusterHosts, bin, *nodes, *tmpDir)
+	if err != nil {
+		return fmt.Errorf("starting cluster: %v", err)
+	}
+	defer c.stop()
+
+	addr := c.addr(0)
+	extraArgs := flag.Args()
+	if *workloadInit {
+		initArgs := append([]string{"workload", "init", *workload, addr}, extraArgs...)
+		if _, err := c.run(bin, initArgs...); err != nil {
+			return fmt.Errorf("workload init: %v", err)
+		}
+	}
+
+	runArgs := append([]string{"workload", "run", *workload,
+		"--concurrency", fmt.Sprint(*concurrency),
+		"--ramp", (*warmup).String(),
+		"--duration", (*duration).String(),
+		addr,
+	}, extraArgs...)
+
+	start := time.Now()
+	out, err := c.run(bin, runArgs...)
+	if err != nil {
+		return fmt.Errorf("workload run: %v", err)
+	}
+	elapsed := time.Since(start) - *warmup
+	stats, err := parseWorkloadOutput(out)
+	if err != nil {
+		return fmt.Errorf("parsing workload output: %v", err)
+	}
+	reportResult(*bench+"/version="+*version, elapsed, stats)
+	return nil
+}
+
+// runCockroachCapture runs the cockroach binary at binPath with args,
+// streaming its output to this process's stdout/stderr and also returning
+// what it wrote to stdout, so callers can parse `cockroach workload run`'s
+// summary output.
+func runCockroachCapture(binPath string, args ...string) (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command(binPath, args...)
+	cmd.Stdout = io.MultiWriter(os.Stdout, &out)
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// runMixedUpgrade benchmarks a cluster undergoing a rolling upgrade from
+// *binOld to *binNew: it starts every node on *binOld, measures a pre-upgrade
+// kv baseline, restarts the nodes one at a time onto *binNew while the kv
+// workload keeps running, finalizes the new cluster version, and measures a
+// post-finalize kv baseline. Each phase reports its own result line under
+// *bench + "/pre", "/during" and "/post-finalize".
+func runMixedUpgrade() error {
+	oldBin := resolveBin(*binOld, *versionOld)
+	newBin := resolveBin(*binNew, *versionNew)
+
+	c, err := startCluster(*clusterKind, *clusterHosts, oldBin, *nodes, *tmpDir)
+	if err != nil {
+		return fmt.Errorf("starting cluster: %v", err)
+	}
+	defer c.stop()
+	addr := c.addr(0)
+
+	if err := runPhaseWorkload(c, oldBin, *bench+"/pre", addr, *duration); err != nil {
+		return err
+	}
+
+	rollNodes := func() error {
+		for i := 0; i < *nodes; i++ {
+			if err := c.restart(i, newBin); err != nil {
+				return fmt.Errorf("restarting node %d onto %s: %v", i, *versionNew, err)
+			}
+		}
+		return nil
+	}
+	if err := runDuringUpgrade(c, newBin, *bench+"/during", addr, *duration, rollNodes); err != nil {
+		return err
+	}
+
+	if err := waitForUpgradeReady(c, newBin, *nodes); err != nil {
+		return fmt.Errorf("waiting for cluster to finish restarting onto %s: %v", *versionNew, err)
+	}
+
+	if _, err := c.run(newBin, "sql", "--insecure", "--host", addr,
+		"-e", "SET CLUSTER SETTING version = crdb_internal.node_executable_version();"); err != nil {
+		return fmt.Errorf("finalizing upgrade to %s: %v", *versionNew, err)
+	}
+
+	return runPhaseWorkload(c, newBin, *bench+"/post-finalize", addr, *duration)
+}
+
+// waitForUpgradeReady polls `SHOW CLUSTER SETTING version` against each of
+// the cluster's nodes until it succeeds, detecting that every node has
+// rejoined the cluster on the new binary. Forcing the version finalize while
+// a node is still restarting would race it, so this gates the finalize on
+// the cluster actually being ready to accept it rather than just on
+// rollNodes having returned.
+func waitForUpgradeReady(c cluster, binPath string, nodes int) error {
+	for i := 0; i < nodes; i++ {
+		addr := c.addr(i)
+		if err := retry(clusterInitAttempts, clusterInitDelay, func() error {
+			_, err := c.run(binPath, "sql", "--insecure", "--host", addr,
+				"-e", "SHOW CLUSTER SETTING version;")
+			return err
+		}); err != nil {
+			return fmt.Errorf("node %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// runPhaseWorkload runs a kv workload against addr for d and reports a
+// single result line under name, for the rolling-upgrade benchmark's pre-
+// and post-upgrade phases, where nothing else is happening to the cluster
+// while it measures. flag.Args() is appended to the workload invocation,
+// e.g. []string{"--read-percent", "95"}.
+func runPhaseWorkload(c cluster, binPath, name, addr string, d time.Duration) error {
+	start := time.Now()
+	out, err := c.run(binPath, append([]string{"workload", "run", "kv",
+		"--concurrency", fmt.Sprint(*concurrency),
+		"--ramp", (*warmup).String(),
+		"--duration", d.String(),
+		addr,
+	}, flag.Args()...)...)
+	if err != nil {
+		return fmt.Errorf("%s workload run: %v", name, err)
+	}
+	stats, err := parseWorkloadOutput(out)
+	if err != nil {
+		return fmt.Errorf("%s: parsing workload output: %v", name, err)
+	}
+	reportResult(name, time.Since(start)-*warmup, stats)
+	return nil
+}
+
+// runDuringUpgrade runs a kv workload against addr for d while rollNodes (the
+// node-by-node restart onto the new binary) runs concurrently, then reports
+// a single result line under name once both finish. Like runPhaseWorkload,
+// it appends flag.Args() to the workload invocation.
+func runDuringUpgrade(c cluster, binPath, name, addr string, d time.Duration, rollNodes func() error) error {
+	var wg sync.WaitGroup
+	var out string
+	var workloadErr error
+	start := time.Now()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		out, workloadErr = c.run(binPath, append([]string{"workload", "run", "kv",
+			"--concurrency", fmt.Sprint(*concurrency),
+			"--ramp", (*warmup).String(),
+			"--duration", d.String(),
+			addr,
+		}, flag.Args()...)...)
+	}()
+	rollErr := rollNodes()
+	wg.Wait()
+	if workloadErr != nil {
+		return fmt.Errorf("%s workload run: %v", name, workloadErr)
+	}
+	if rollErr != nil {
+		return fmt.Errorf("rolling nodes: %v", rollErr)
+	}
+	stats, err := parseWorkloadOutput(out)
+	if err != nil {
+		return fmt.Errorf("%s: parsing workload output: %v", name, err)
+	}
+	reportResult(name, time.Since(start)-*warmup, stats)
+	return nil
+}
+
+// workloadStats holds the workload-native metrics parsed out of a
+// `cockroach workload run` transcript, which vary by generator: every
+// workload reports ops/sec and tail latency, but tpmC is tpcc-specific.
+type workloadStats struct {
+	opsPerSec float64
+	p95Millis float64
+	tpmC      float64 // 0 if the workload doesn't report it (only tpcc does)
+}
+
+// parseWorkloadOutput scans a `cockroach workload run` transcript for its
+// final "_elapsed" summary line and extracts the metrics reportResult needs.
+// workload prints a header naming each column (e.g. "ops/sec(cum)", "tpmC",
+// "p95(ms)") followed by a whitespace-aligned data row in the same column
+// order; the last such pair in the transcript is the run's overall summary.
+func parseWorkloadOutput(output string) (workloadStats, error) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	for i := len(lines) - 1; i > 0; i-- {
+		data := strings.Fields(lines[i])
+		header := strings.Fields(lines[i-1])
+		if len(data) == 0 || len(data) != len(header) || !strings.Contains(lines[i-1], "ops/sec") {
+			continue
+		}
+		return statsFromColumns(header, data)
+	}
+	return workloadStats{}, fmt.Errorf("no workload summary line found in output")
+}
+
+// statsFromColumns maps a workload summary's header/data row pair into a
+// workloadStats, matching columns by name since their order and presence
+// vary by generator.
+func statsFromColumns(header, data []string) (workloadStats, error) {
+	var s workloadStats
+	found := false
+	for i, col := range header {
+		v, err := strconv.ParseFloat(data[i], 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case strings.Contains(col, "ops/sec(cum)"):
+			s.opsPerSec = v
+			found = true
+		case strings.Contains(col, "p95"):
+			s.p95Millis = v
+		case strings.Contains(col, "tpmC"):
+			s.tpmC = v
+		}
+	}
+	if !found {
+		return workloadStats{}, fmt.Errorf("summary line missing ops/sec(cum) column")
+	}
+	return s, nil
+}
+
+// reportResult prints a result in Go benchmark format, the format benchstat
+// expects: name must start with an uppercase rune right after "Benchmark",
+// so name's first rune is capitalized regardless of the workload naming
+// convention that produced it (e.g. "kv0/nodes=1").
+func reportResult(name string, elapsed time.Duration, stats workloadStats) {
+	fmt.Printf("Benchmark%s 1 %d ns/op %.2f ops/sec", capitalize(name), elapsed.Nanoseconds(), stats.opsPerSec)
+	if stats.p95Millis > 0 {
+		fmt.Printf(" %.2f p95-ms", stats.p95Millis)
+	}
+	if stats.tpmC > 0 {
+		fmt.Printf(" %.2f tpmC", stats.tpmC)
+	}
+	fmt.Println()
+}
+
+// capitalize upper-cases s's first rune, leaving the rest of s untouched.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// clusterInitAttempts and clusterInitDelay bound how long startCluster waits
+// for a freshly started multi-node cluster to accept `cockroach init`: nodes
+// need a moment after starting before their RPC listener is ready.
+const (
+	clusterInitAttempts = 30
+	clusterInitDelay    = time.Second
+)
+
+// cluster abstracts the node-management operations runWorkload and
+// runMixedUpgrade need, regardless of whether the nodes run on this machine
+// (localCluster) or over SSH on cockroachDBClusterHosts (remoteCluster).
+type cluster interface {
+	// addr returns the SQL/RPC address of node i.
+	addr(i int) string
+	// run executes `binPath args...` on whichever machine drives this
+	// cluster's workload, streaming output to stdout/stderr and also
+	// returning what it wrote to stdout so callers can parse workload
+	// summaries.
+	run(binPath string, args ...string) (string, error)
+	// restart stops node i (if running) and starts it again using binPath,
+	// used by the rolling-upgrade benchmark to swap a node onto a new
+	// version without tearing down the rest of the cluster.
+	restart(i int, binPath string) error
+	// stop shuts down every node in the cluster.
+	stop()
+}
+
+// remoteBinPath returns the path cockroachdb-bench should use to invoke a
+// given version's cockroach binary on a cockroachDBClusterHosts machine,
+// matching where the harness's scpToHosts copies it to (see
+// sweet/harnesses/cockroachdb.go's Run).
+func remoteBinPath(version string) string {
+	return "~/cockroach-" + version
+}
+
+// resolveBin returns the cockroach binary cockroachdb-bench should actually
+// invoke for version: localPath directly in local mode, since it's a path on
+// this machine, or the path the harness scp'd it to on
+// cockroachDBClusterHosts when running against the remote backend.
+func resolveBin(localPath, version string) string {
+	if *clusterKind == "remote" {
+		return remoteBinPath(version)
+	}
+	return localPath
+}
+
+// startCluster starts a nodes-sized cluster using the given backend (as
+// named by the -cluster flag) and, for multi-node clusters, bootstraps it
+// with `cockroach init` before returning.
+func startCluster(kind, hostsCSV, binPath string, nodes int, workDir string) (cluster, error) {
+	var c cluster
+	var err error
+	switch kind {
+	case "local":
+		c, err = newLocalCluster(binPath, nodes, workDir)
+	case "remote":
+		c, err = newRemoteCluster(strings.Split(hostsCSV, ","), binPath, nodes, workDir)
+	default:
+		return nil, fmt.Errorf("cluster backend %q not supported", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if nodes > 1 {
+		if err := retry(clusterInitAttempts, clusterInitDelay, func() error {
+			_, err := c.run(binPath, "init", "--insecure", "--host", c.addr(0))
+			return err
+		}); err != nil {
+			c.stop()
+			return nil, fmt.Errorf("initializing cluster: %v", err)
+		}
+	}
+	return c, nil
+}
+
+// retry calls fn until it succeeds or n attempts are exhausted, sleeping
+// delay between attempts.
+func retry(n int, delay time.Duration, fn func() error) error {
+	var lastErr error
+	for i := 0; i < n; i++ {
+		if lastErr = fn(); lastErr == nil {
+			return nil
+		}
+		time.Sleep(delay)
+	}
+	return lastErr
+}
+
+// localBasePort is the SQL/RPC port the first local node listens on; each
+// subsequent node takes the next port, matching how `cockroach demo`-style
+// local clusters stack up nodes on one machine.
+const localBasePort = 26257
+
+// localCluster is a set of cockroach node processes started on the local
+// machine, one per node in the requested cluster size, joined into a single
+// cluster rather than nodes independent single-node clusters.
+type localCluster struct {
+	workDir   string
+	joinAddrs []string
+	procs     []*os.Process
+}
+
+// newLocalCluster starts a nodes-sized cockroach cluster under workDir using
+// the binary at binPath, all on the local machine. For nodes > 1 the nodes
+// are joined into one cluster via --join; the caller still needs to run
+// `cockroach init` once before it's usable.
+func newLocalCluster(binPath string, nodes int, workDir string) (*localCluster, error) {
+	c := &localCluster{workDir: workDir}
+	c.joinAddrs = make([]string, nodes)
+	for i := range c.joinAddrs {
+		c.joinAddrs[i] = fmt.Sprintf("localhost:%d", localBasePort+i)
+	}
+	c.procs = make([]*os.Process, nodes)
+	for i := range c.joinAddrs {
+		if err := c.startNode(i, binPath); err != nil {
+			c.stop()
+			return nil, fmt.Errorf("starting node %d: %v", i, err)
+		}
+	}
+	return c, nil
+}
+
+// startNode (re-)starts node i using binPath, replacing whatever process
+// previously occupied c.procs[i].
+func (c *localCluster) startNode(i int, binPath string) error {
+	storeDir := filepath.Join(c.workDir, fmt.Sprintf("n%d", i))
+	if err := os.MkdirAll(storeDir, 0755); err != nil {
+		return err
+	}
+	args := []string{"start", "--insecure",
+		"--store", storeDir,
+		"--listen-addr", c.joinAddrs[i],
+	}
+	if len(c.joinAddrs) == 1 {
+		args[0] = "start-single-node"
+	} else {
+		args = append(args, "--join", strings.Join(c.joinAddrs, ","))
+	}
+	// Run in the foreground (no --background) so cmd.Process is the actual
+	// server, letting stop/restart kill it directly instead of relying on a
+	// daemonized process we have no handle to.
+	cmd := exec.Command(binPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	c.procs[i] = cmd.Process
+	return nil
+}
+
+// addr returns the "host:port" address of the i'th node, suitable for use as
+// a `cockroach workload` target.
+func (c *localCluster) addr(i int) string {
+	return c.joinAddrs[i]
+}
+
+// run executes `binPath args...` on the local machine and returns what it
+// wrote to stdout.
+func (c *localCluster) run(binPath string, args ...string) (string, error) {
+	return runCockroachCapture(binPath, args...)
+}
+
+// restart stops node i and starts it again on binPath.
+func (c *localCluster) restart(i int, binPath string) error {
+	if p := c.procs[i]; p != nil {
+		_ = p.Kill()
+		_, _ = p.Wait()
+	}
+	return c.startNode(i, binPath)
+}
+
+// stop best-effort shuts down every node in the cluster.
+func (c *localCluster) stop() {
+	for _, p := range c.procs {
+		if p != nil {
+			_ = p.Kill()
+		}
+	}
+}
+
+// remotePort is the port cockroach listens on on every cockroachDBClusterHosts
+// machine. Unlike localCluster's nodes, remote nodes each get their own
+// machine, so there's no need to stack them on successive ports.
+const remotePort = 26257
+
+// remoteCluster is a set of cockroach nodes started over SSH, one per host
+// in cockroachDBClusterHosts, joined into a single cluster. Nodes are run
+// with --background since, unlike localCluster, there's no cmd.Process to
+// hold onto across an SSH connection; stop/restart instead pkill the remote
+// process by name, matching the harness's teardownRemoteCluster.
+type remoteCluster struct {
+	// hosts are the "user@host" SSH targets, one per node. hosts[0] also
+	// doubles as the client node that drives `cockroach workload`/`sql`,
+	// matching the cockroachDBClusterHosts doc comment in the harness.
+	hosts     []string
+	joinAddrs []string
+	workDir   string
+}
+
+// newRemoteCluster starts a nodes-sized cockroach cluster on the first nodes
+// hosts, one node per host, using the binary at binPath (a path on the
+// remote hosts, not this machine — see resolveBin). For nodes > 1 the nodes
+// are joined into one cluster via --join; the caller still needs to run
+// `cockroach init` once before it's usable.
+func newRemoteCluster(hosts []string, binPath string, nodes int, workDir string) (*remoteCluster, error) {
+	if len(hosts) < nodes {
+		return nil, fmt.Errorf("%d hosts given, need at least %d for a %d-node cluster", len(hosts), nodes, nodes)
+	}
+	hosts = hosts[:nodes]
+
+	c := &remoteCluster{hosts: hosts, workDir: workDir}
+	c.joinAddrs = make([]string, len(hosts))
+	for i, host := range hosts {
+		c.joinAddrs[i] = fmt.Sprintf("%s:%d", sshHostname(host), remotePort)
+	}
+	for i := range hosts {
+		if err := c.startNode(i, binPath); err != nil {
+			c.stop()
+			return nil, fmt.Errorf("starting node on %s: %v", hosts[i], err)
+		}
+	}
+	return c, nil
+}
+
+// startNode (re-)starts node i using binPath.
+func (c *remoteCluster) startNode(i int, binPath string) error {
+	args := []string{binPath, "start", "--insecure",
+		"--store", c.workDir,
+		"--listen-addr", c.joinAddrs[i],
+		"--advertise-addr", c.joinAddrs[i],
+		"--background",
+	}
+	if len(c.hosts) == 1 {
+		args[1] = "start-single-node"
+	} else {
+		args = append(args, "--join", strings.Join(c.joinAddrs, ","))
+	}
+	return runSSH(c.hosts[i], args...)
+}
+
+// addr returns the "host:port" address of the i'th node, suitable for use as
+// a `cockroach workload` target.
+func (c *remoteCluster) addr(i int) string {
+	return c.joinAddrs[i]
+}
+
+// run executes `binPath args...` over SSH on this cluster's client node
+// (hosts[0]) and returns what it wrote to stdout.
+func (c *remoteCluster) run(binPath string, args ...string) (string, error) {
+	return runSSHCapture(c.hosts[0], append([]string{binPath}, args...))
+}
+
+// restart stops node i and starts it again on binPath.
+func (c *remoteCluster) restart(i int, binPath string) error {
+	_ = runSSH(c.hosts[i], "pkill", "cockroach", "||", "true")
+	return c.startNode(i, binPath)
+}
+
+// stop best-effort shuts down every node in the cluster.
+func (c *remoteCluster) stop() {
+	for _, host := range c.hosts {
+		_ = runSSH(host, "pkill", "cockroach", "||", "true")
+	}
+}
+
+// sshHostname strips the "user@" prefix (if any) off an SSH target, leaving
+// the bare hostname cockroach should advertise and listen on.
+func sshHostname(host string) string {
+	if i := strings.IndexByte(host, '@'); i >= 0 {
+		return host[i+1:]
+	}
+	return host
+}
+
+// runSSH runs args as a single command on host over SSH, streaming its
+// output to this process's stdout/stderr.
+func runSSH(host string, args ...string) error {
+	cmd := exec.Command("ssh", host, strings.Join(args, " "))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// runSSHCapture behaves like runSSH but also returns everything written to
+// stdout, so callers can parse `cockroach workload run`'s summary output.
+func runSSHCapture(host string, args []string) (string, error) {
+	var out bytes.Buffer
+	cmd := exec.Command("ssh", host, strings.Join(args, " "))
+	cmd.Stdout = io.MultiWriter(os.Stdout, &out)
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	return out.String(), err
+}
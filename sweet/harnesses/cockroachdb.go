@@ -5,16 +5,285 @@
 package harnesses
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"golang.org/x/benchmarks/sweet/common"
 	"golang.org/x/benchmarks/sweet/common/log"
 )
 
+// stringListFlag is a flag.Value for the handful of cockroachdb flags that
+// take a comma-separated list rather than a single value, e.g.
+// "v23.2.0,v24.1.0-rc.1".
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(s string) error {
+	*f = strings.Split(s, ",")
+	return nil
+}
+
+// builtSHAFile is the sidecar file written alongside the cockroach binary
+// recording the git SHA of the checkout it was built from, so that
+// subsequent Build calls can skip rebuilding unchanged checkouts entirely.
+const builtSHAFile = ".built-sha"
+
+// cockroachDBVersions is the set of CockroachDB refs that are built and
+// benchmarked. Results are tagged with the ref they came from so that
+// benchstat can compare across versions, e.g. v23.2, v24.1, v24.2 and
+// master in a single sweet invocation.
+var cockroachDBVersions = []string{
+	"v23.2.0",
+	"v24.1.0-rc.1",
+}
+
+// cockroachDBReleaseBuild controls whether the harness asks bazel for an
+// optimized release build (`-c opt`, unstripped) instead of accepting
+// whatever dbg/fastbuild default the host's bazel config provides. CRDB
+// ships release binaries, so leaving this off materially skews wall-clock
+// numbers.
+var cockroachDBReleaseBuild = true
+
+func init() {
+	flag.Var((*stringListFlag)(&cockroachDBVersions), "cockroachdb-version",
+		"comma-separated list of CockroachDB refs to build and benchmark")
+	flag.BoolVar(&cockroachDBReleaseBuild, "cockroachdb-release-build", cockroachDBReleaseBuild,
+		"build an optimized, unstripped release binary instead of the bazel host config's dbg/fastbuild default")
+}
+
+// cockroachDBUpgrade names the "old" and "new" refs used by the mixed-version
+// rolling-upgrade benchmark. Both must also appear in cockroachDBVersions so
+// that Get/Build produce binaries for them.
+var cockroachDBUpgrade = struct {
+	From, To string
+}{
+	From: "v23.2.0",
+	To:   "v24.1.0-rc.1",
+}
+
+func init() {
+	flag.StringVar(&cockroachDBUpgrade.From, "cockroachdb-upgrade-from", cockroachDBUpgrade.From,
+		"version the mixed-version upgrade benchmark's cluster starts on")
+	flag.StringVar(&cockroachDBUpgrade.To, "cockroachdb-upgrade-to", cockroachDBUpgrade.To,
+		"version the mixed-version upgrade benchmark upgrades its cluster to")
+}
+
+// cockroachDBUpgradeNodes, cockroachDBUpgradeConcurrency,
+// cockroachDBUpgradeWarmup and cockroachDBUpgradeDuration configure the kv
+// workload that runs throughout the mixed-version rolling-upgrade benchmark,
+// matching the plain kv0/50/95 nodes=3 specs' concurrency so the
+// pre/during/post-finalize phases are comparable to the equivalent
+// single-version kv benchmarks.
+const (
+	cockroachDBUpgradeNodes       = 3
+	cockroachDBUpgradeConcurrency = 250
+	cockroachDBUpgradeWarmup      = 10 * time.Second
+	cockroachDBUpgradeDuration    = 30 * time.Second
+)
+
+// cockroachDBClusterHosts lists the "user@host" SSH targets the benchmark
+// cluster is provisioned on. When empty, the cluster runs entirely on the
+// local machine (loopback RPCs); when set, cockroachdb-bench starts one node
+// per host over SSH, which is necessary to see real cross-node RPC latency
+// in codepaths like raft and DistSQL. The first host is used as the client
+// node that drives the workload.
+var cockroachDBClusterHosts []string
+
+func init() {
+	flag.Var((*stringListFlag)(&cockroachDBClusterHosts), "cockroachdb-cluster-hosts",
+		"comma-separated user@host SSH targets to provision the benchmark cluster on; empty runs locally")
+}
+
+// scpToHosts copies the file at localPath to remotePath on each of hosts.
+func scpToHosts(localPath, remotePath string, hosts []string) error {
+	for _, host := range hosts {
+		cmd := exec.Command("scp", localPath, host+":"+remotePath)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("scp to %s: %v", host, err)
+		}
+	}
+	return nil
+}
+
+// teardownRemoteCluster best-effort stops any cockroach process left running
+// on hosts. It's called after a failed run so that a dead benchmark doesn't
+// leave a cluster wedged for the next invocation.
+func teardownRemoteCluster(hosts []string) {
+	for _, host := range hosts {
+		cmd := exec.Command("ssh", host, "pkill cockroach || true")
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		_ = cmd.Run()
+	}
+}
+
+// clusterArgs returns the cockroachdb-bench flags describing the cluster
+// backend to use: the local, single-machine backend when
+// cockroachDBClusterHosts is empty, or the SSH-provisioned remote backend
+// otherwise.
+func clusterArgs() []string {
+	if len(cockroachDBClusterHosts) == 0 {
+		return []string{"-cluster", "local"}
+	}
+	return []string{"-cluster", "remote", "-cluster-hosts", strings.Join(cockroachDBClusterHosts, ",")}
+}
+
+// WorkloadSpec describes a single `cockroach workload` benchmark to run
+// against a built cluster. It captures everything cockroachdb-bench needs to
+// drive the workload and to map its native metrics (tpmC, ops/sec, per-query
+// latency, ...) into Go benchmark output lines that benstat can consume
+// alongside the plain kv results.
+type WorkloadSpec struct {
+	// Name is the benchmark name reported in results, e.g. "kv0/nodes=3",
+	// "tpcc/warehouses=10" or "ycsb/A".
+	Name string
+	// Workload is the `cockroach workload` generator name, e.g. "kv",
+	// "tpcc", "ycsb" or "tpch".
+	Workload string
+	// NeedsInit indicates the workload requires a `cockroach workload init`
+	// pass (with the same ExtraArgs) to load its schema and fixtures before
+	// it can be run; kv and ycsb self-insert and don't need this.
+	NeedsInit bool
+	// Nodes is the size of the cluster the workload is run against.
+	Nodes int
+	// Concurrency is the number of concurrent workload connections.
+	Concurrency int
+	Warmup      time.Duration
+	Duration    time.Duration
+	// ExtraArgs are appended verbatim to the `cockroach workload run`
+	// invocation, e.g. []string{"--warehouses", "10"}.
+	ExtraArgs []string
+}
+
+// cockroachDBWorkloads is the set of workloads run against each version in
+// cockroachDBVersions.
+var cockroachDBWorkloads = []WorkloadSpec{
+	{Name: "kv0/nodes=1", Workload: "kv", Nodes: 1, Concurrency: 250, Duration: 30 * time.Second, ExtraArgs: []string{"--read-percent", "0"}},
+	{Name: "kv50/nodes=1", Workload: "kv", Nodes: 1, Concurrency: 250, Duration: 30 * time.Second, ExtraArgs: []string{"--read-percent", "50"}},
+	{Name: "kv95/nodes=1", Workload: "kv", Nodes: 1, Concurrency: 250, Duration: 30 * time.Second, ExtraArgs: []string{"--read-percent", "95"}},
+	{Name: "kv0/nodes=3", Workload: "kv", Nodes: 3, Concurrency: 250, Duration: 30 * time.Second, ExtraArgs: []string{"--read-percent", "0"}},
+	{Name: "kv50/nodes=3", Workload: "kv", Nodes: 3, Concurrency: 250, Duration: 30 * time.Second, ExtraArgs: []string{"--read-percent", "50"}},
+	{Name: "kv95/nodes=3", Workload: "kv", Nodes: 3, Concurrency: 250, Duration: 30 * time.Second, ExtraArgs: []string{"--read-percent", "95"}},
+	{
+		Name:        "tpcc/warehouses=10",
+		Workload:    "tpcc",
+		NeedsInit:   true,
+		Nodes:       3,
+		Concurrency: 50,
+		Warmup:      30 * time.Second,
+		Duration:    time.Minute,
+		ExtraArgs: []string{
+			"--warehouses", "10",
+		},
+	},
+	{
+		Name:        "ycsb/A/nodes=3",
+		Workload:    "ycsb",
+		Nodes:       3,
+		Concurrency: 100,
+		Warmup:      15 * time.Second,
+		Duration:    30 * time.Second,
+		ExtraArgs:   []string{"--workload", "A"},
+	},
+	{
+		Name:        "ycsb/B/nodes=3",
+		Workload:    "ycsb",
+		Nodes:       3,
+		Concurrency: 100,
+		Warmup:      15 * time.Second,
+		Duration:    30 * time.Second,
+		ExtraArgs:   []string{"--workload", "B"},
+	},
+	{
+		Name:        "ycsb/C/nodes=3",
+		Workload:    "ycsb",
+		Nodes:       3,
+		Concurrency: 100,
+		Warmup:      15 * time.Second,
+		Duration:    30 * time.Second,
+		ExtraArgs:   []string{"--workload", "C"},
+	},
+	{
+		Name:        "ycsb/D/nodes=3",
+		Workload:    "ycsb",
+		Nodes:       3,
+		Concurrency: 100,
+		Warmup:      15 * time.Second,
+		Duration:    30 * time.Second,
+		ExtraArgs:   []string{"--workload", "D"},
+	},
+	{
+		Name:        "ycsb/E/nodes=3",
+		Workload:    "ycsb",
+		Nodes:       3,
+		Concurrency: 100,
+		Warmup:      15 * time.Second,
+		Duration:    30 * time.Second,
+		ExtraArgs:   []string{"--workload", "E"},
+	},
+	{
+		Name:        "ycsb/F/nodes=3",
+		Workload:    "ycsb",
+		Nodes:       3,
+		Concurrency: 100,
+		Warmup:      15 * time.Second,
+		Duration:    30 * time.Second,
+		ExtraArgs:   []string{"--workload", "F"},
+	},
+	{
+		Name:        "tpch/sf=1",
+		Workload:    "tpch",
+		NeedsInit:   true,
+		Nodes:       3,
+		Concurrency: 1,
+		Duration:    time.Minute,
+		ExtraArgs:   []string{"--scale-factor", "1"},
+	},
+}
+
+// cockroachDBWorkloadFilter, when non-empty, restricts cockroachDBWorkloads
+// to just the named entries (matched against WorkloadSpec.Name), letting a
+// single sweet invocation target one workload instead of the whole matrix.
+var cockroachDBWorkloadFilter stringListFlag
+
+func init() {
+	flag.Var(&cockroachDBWorkloadFilter, "cockroachdb-workloads",
+		"comma-separated subset of cockroachDBWorkloads' names to run; empty runs all of them")
+}
+
+// selectedWorkloads returns cockroachDBWorkloads filtered down to
+// cockroachDBWorkloadFilter when it's set, or the full matrix otherwise.
+func selectedWorkloads() []WorkloadSpec {
+	if len(cockroachDBWorkloadFilter) == 0 {
+		return cockroachDBWorkloads
+	}
+	want := make(map[string]bool, len(cockroachDBWorkloadFilter))
+	for _, name := range cockroachDBWorkloadFilter {
+		want[name] = true
+	}
+	var out []WorkloadSpec
+	for _, w := range cockroachDBWorkloads {
+		if want[w.Name] {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
 // CockroachDB implements the Harness interface.
 type CockroachDB struct{}
 
@@ -26,19 +295,38 @@ func (h CockroachDB) CheckPrerequisites() error {
 	return nil
 }
 
+// versionDir joins base with version, giving each ref in the benchmark
+// matrix its own cached subtree, e.g. ".../src/v24.1.0-rc.1".
+func versionDir(base, version string) string {
+	return filepath.Join(base, version)
+}
+
 func (h CockroachDB) Get(gcfg *common.GetConfig) error {
-	// Build against the latest stable release.
+	// Fetch each version in the benchmark matrix into its own cached
+	// checkout, keyed by ref, so re-running Get doesn't re-clone refs we
+	// already have.
+	//
 	// Deep clone the repo as we need certain submodules, i.e.
 	// PROJ, for the build to work.
-	return gitDeepClone(
-		gcfg.SrcDir,
-		"https://github.com/cockroachdb/cockroach",
-		"v24.1.0-rc.1",
-	)
+	for _, version := range cockroachDBVersions {
+		srcDir := versionDir(gcfg.SrcDir, version)
+		if _, err := os.Stat(srcDir); err == nil {
+			// Already cloned this ref.
+			continue
+		}
+		if err := gitDeepClone(
+			srcDir,
+			"https://github.com/cockroachdb/cockroach",
+			version,
+		); err != nil {
+			return fmt.Errorf("cloning %s: %v", version, err)
+		}
+	}
+	return nil
 }
 
 func (h CockroachDB) Build(cfg *common.Config, bcfg *common.BuildConfig) error {
-	// Build the cockroach binary.
+	// Build the cockroach binary for each version in the matrix.
 	// We do this by using the cockroach `dev` tool. The dev tool is a bazel
 	// wrapper normally used for building cockroach, but can also be used to
 	// generate artifacts that can then be built by `go build`.
@@ -48,27 +336,117 @@ func (h CockroachDB) Build(cfg *common.Config, bcfg *common.BuildConfig) error {
 		return fmt.Errorf("error building bazelisk: %v", err)
 	}
 
-	// Clean up the bazel workspace. If we don't do this, our _bazel directory
-	// will quickly grow as Bazel treats each run as its own workspace with its
-	// own artifacts.
-	defer func() {
-		cmd := exec.Command("bazel", "clean", "--expunge")
-		cmd.Dir = bcfg.SrcDir
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		// Cleanup is best effort, there might not be anything to clean up
-		// if we fail early enough in the build process.
-		_ = cmd.Run()
-	}()
+	for _, version := range cockroachDBVersions {
+		srcDir := versionDir(bcfg.SrcDir, version)
+		binDir := versionDir(bcfg.BinDir, version)
+		if err := os.MkdirAll(binDir, 0755); err != nil {
+			return fmt.Errorf("creating bin dir for %s: %v", version, err)
+		}
+		if err := h.buildVersion(cfg, srcDir, binDir); err != nil {
+			return fmt.Errorf("building %s: %v", version, err)
+		}
+	}
+
+	// Build the benchmark wrapper once; it's version-independent and is
+	// pointed at whichever per-version binary it needs at run time.
+	if err := cfg.GoTool().BuildPath(bcfg.BenchDir, filepath.Join(bcfg.BinDir, "cockroachdb-bench")); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("chmod", "-R", "755", filepath.Join(bcfg.BinDir, "cockroachdb-bench"))
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// bazelConfigArgs returns the extra `bazel run` arguments needed to get a
+// release (optimized, unstripped) build when cockroachDBReleaseBuild is set,
+// or nil for the bazel default.
+func bazelConfigArgs() []string {
+	if !cockroachDBReleaseBuild {
+		return nil
+	}
+	return []string{"-c", "opt", "--strip=never"}
+}
+
+// goBuildArgs returns the extra `go build` flags needed so that
+// cockroachDBReleaseBuild's effect actually reaches the cockroach binary
+// that gets benchmarked. bazelConfigArgs only covers the codegen/cgo steps
+// above; the binary itself is finished off with a plain `go build`, which
+// never sees bazel's `-c opt`/`--strip` flags, so we mirror opt vs. dbg here
+// directly instead.
+func goBuildArgs() []string {
+	if cockroachDBReleaseBuild {
+		// `go build` is already optimized and unstripped by default, which
+		// matches bazel's `-c opt --strip=never`.
+		return nil
+	}
+	// Mirror bazel's dbg config, which disables optimizations and inlining
+	// for a better debugging experience at the cost of wall-clock time.
+	return []string{"-gcflags", "all=-N -l"}
+}
+
+// gitHeadSHA returns the git commit SHA that srcDir is currently checked out
+// to.
+func gitHeadSHA(srcDir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = srcDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse HEAD: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// buildCacheKey returns the value buildVersion stamps into builtSHAFile for
+// a checkout at the given SHA: the SHA itself plus whatever build-affecting
+// settings change the resulting binary, so that flipping one of those
+// settings invalidates the cache instead of silently reusing a binary built
+// under the old one.
+func buildCacheKey(sha string) string {
+	return sha + " release=" + fmt.Sprint(cockroachDBReleaseBuild)
+}
+
+// buildVersion builds a single CockroachDB checkout at srcDir, placing the
+// resulting `cockroach` binary in binDir. If binDir already holds a
+// cockroach binary built from the SHA that srcDir currently points at, under
+// the same build settings, the build is skipped entirely.
+func (h CockroachDB) buildVersion(cfg *common.Config, srcDir, binDir string) error {
+	sha, err := gitHeadSHA(srcDir)
+	if err != nil {
+		return err
+	}
+	cacheKey := buildCacheKey(sha)
+	shaFile := filepath.Join(binDir, builtSHAFile)
+	if built, err := os.ReadFile(shaFile); err == nil && strings.TrimSpace(string(built)) == cacheKey {
+		// binDir already holds a binary built from this exact checkout and
+		// these exact build settings.
+		return nil
+	}
+
+	// Point bazel at a persistent, version-keyed output root under the
+	// harness's asset cache instead of letting it default to a per-checkout
+	// workspace. Since each version has its own root and we only rebuild a
+	// given ref when its SHA has changed, there's no need to `bazel clean
+	// --expunge` between builds: warm rebuilds reuse the existing code-gen
+	// and cgo artifacts instead of redoing them from scratch.
+	outputRoot := filepath.Join(cfg.AssetsCache, "cockroachdb-bazel", filepath.Base(srcDir))
+	if err := os.MkdirAll(outputRoot, 0755); err != nil {
+		return fmt.Errorf("creating bazel output root: %v", err)
+	}
+	bazelStartupArgs := []string{"--output_user_root", outputRoot}
 
 	// Configure the build env.
 	env := cfg.BuildEnv.Env
 	env = env.Prefix("PATH", filepath.Join(cfg.GoRoot, "bin")+":")
 	env = env.MustSet("GOROOT=" + cfg.GoRoot)
 
+	bazelArgs := bazelConfigArgs()
+
 	// Use bazel to generate the artifacts needed to enable a `go build`.
-	cmd := exec.Command("bazel", "run", "//pkg/gen:code")
-	cmd.Dir = bcfg.SrcDir
+	cmd := exec.Command("bazel", append(append(bazelStartupArgs, "run", "//pkg/gen:code"), bazelArgs...)...)
+	cmd.Dir = srcDir
 	cmd.Env = env.Collapse()
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -77,8 +455,8 @@ func (h CockroachDB) Build(cfg *common.Config, bcfg *common.BuildConfig) error {
 	}
 
 	// Build the c-deps needed.
-	cmd = exec.Command("bazel", "run", "//pkg/cmd/generate-cgo:generate-cgo", "--run_under", fmt.Sprintf("cd %s && ", bcfg.SrcDir))
-	cmd.Dir = bcfg.SrcDir
+	cmd = exec.Command("bazel", append(append(bazelStartupArgs, "run", "//pkg/cmd/generate-cgo:generate-cgo", "--run_under", fmt.Sprintf("cd %s && ", srcDir)), bazelArgs...)...)
+	cmd.Dir = srcDir
 	cmd.Env = env.Collapse()
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -89,38 +467,125 @@ func (h CockroachDB) Build(cfg *common.Config, bcfg *common.BuildConfig) error {
 	// Finally build the cockroach binary with `go build`. Build the
 	// cockroach-short binary as it is functionally the same, but
 	// without the UI, making it much quicker to build.
-	if err := cfg.GoTool().BuildPath(filepath.Join(bcfg.SrcDir, "pkg/cmd/cockroach-short"), bcfg.BinDir); err != nil {
+	if err := cfg.GoTool().BuildPath(filepath.Join(srcDir, "pkg/cmd/cockroach-short"), binDir, goBuildArgs()...); err != nil {
 		return err
 	}
 
 	// Rename the binary from cockroach-short to cockroach for
 	// ease of use.
-	if err := copyFile(filepath.Join(bcfg.BinDir, "cockroach"), filepath.Join(bcfg.BinDir, "cockroach-short")); err != nil {
+	if err := copyFile(filepath.Join(binDir, "cockroach"), filepath.Join(binDir, "cockroach-short")); err != nil {
 		return err
 	}
 
-	// Build the benchmark wrapper.
-	if err := cfg.GoTool().BuildPath(bcfg.BenchDir, filepath.Join(bcfg.BinDir, "cockroachdb-bench")); err != nil {
-		return err
+	// Record the SHA and build settings we just built with so the next
+	// Build call can short-circuit if neither has changed.
+	if err := os.WriteFile(shaFile, []byte(cacheKey), 0644); err != nil {
+		return fmt.Errorf("writing %s: %v", shaFile, err)
 	}
+	return nil
+}
 
-	cmd = exec.Command("chmod", "-R", "755", filepath.Join(bcfg.BinDir, "cockroachdb-bench"))
-	if err := cmd.Run(); err != nil {
-		return err
+func (h CockroachDB) Run(cfg *common.Config, rcfg *common.RunConfig) (err error) {
+	if len(cockroachDBClusterHosts) > 0 {
+		// Tear the cluster down if anything below fails, so a wedged remote
+		// cluster doesn't poison the next run.
+		defer func() {
+			if err != nil {
+				teardownRemoteCluster(cockroachDBClusterHosts)
+			}
+		}()
+		for _, version := range cockroachDBVersions {
+			bin := filepath.Join(versionDir(rcfg.BinDir, version), "cockroach")
+			if err := scpToHosts(bin, "~/cockroach-"+version, cockroachDBClusterHosts); err != nil {
+				return err
+			}
+		}
 	}
-	return nil
+
+	for _, version := range cockroachDBVersions {
+		binDir := versionDir(rcfg.BinDir, version)
+		for _, w := range selectedWorkloads() {
+			args := append(rcfg.Args, []string{
+				"-bench", w.Name,
+				"-workload", w.Workload,
+				"-cockroachdb-bin", filepath.Join(binDir, "cockroach"),
+				"-cockroachdb-version", version,
+				"-nodes", fmt.Sprint(w.Nodes),
+				"-concurrency", fmt.Sprint(w.Concurrency),
+				"-warmup", w.Warmup.String(),
+				"-duration", w.Duration.String(),
+				"-tmp", rcfg.TmpDir,
+			}...)
+			args = append(args, clusterArgs()...)
+			if w.NeedsInit {
+				args = append(args, "-workload-init")
+			}
+			if rcfg.Short {
+				args = append(args, "-short")
+			}
+			// Everything past "--" is forwarded verbatim to `cockroach
+			// workload run`, rather than parsed by cockroachdb-bench itself:
+			// ExtraArgs varies per workload (--read-percent for kv,
+			// --warehouses for tpcc, --workload for ycsb, ...), so requiring
+			// cockroachdb-bench to register every generator's flags up front
+			// doesn't scale, and some of those names (e.g. --workload)
+			// collide with cockroachdb-bench's own flags of the same name.
+			args = append(args, "--")
+			args = append(args, w.ExtraArgs...)
+			cmd := exec.Command(
+				filepath.Join(rcfg.BinDir, "cockroachdb-bench"),
+				args...,
+			)
+			cmd.Env = cfg.ExecEnv.Collapse()
+			cmd.Stdout = rcfg.Results
+			cmd.Stderr = rcfg.Results
+			log.TraceCommand(cmd, false)
+			if err := cmd.Run(); err != nil {
+				return err
+			}
+			// Delete tmp because cockroachdb will have written something there and
+			// might attempt to reuse it. We don't want to reuse the same cluster.
+			if err := rmDirContents(rcfg.TmpDir); err != nil {
+				return err
+			}
+		}
+	}
+	return h.runMixedVersionUpgrade(cfg, rcfg)
 }
 
-func (h CockroachDB) Run(cfg *common.Config, rcfg *common.RunConfig) error {
-	for _, bench := range []string{"kv0/nodes=1", "kv50/nodes=1", "kv95/nodes=1", "kv0/nodes=3", "kv50/nodes=3", "kv95/nodes=3"} {
+// runMixedVersionUpgrade benchmarks a 3-node cluster undergoing a rolling
+// upgrade from cockroachDBUpgrade.From to cockroachDBUpgrade.To, modeled on
+// the cockroach-go testserver upgrade pattern: all nodes start on the old
+// binary, a kv workload runs throughout, and the bench wrapper swaps and
+// restarts nodes one at a time before finalizing the new cluster version.
+// It emits separate benchmark lines for the pre-upgrade, during-upgrade and
+// post-upgrade-finalized phases.
+func (h CockroachDB) runMixedVersionUpgrade(cfg *common.Config, rcfg *common.RunConfig) error {
+	oldBin := filepath.Join(versionDir(rcfg.BinDir, cockroachDBUpgrade.From), "cockroach")
+	newBin := filepath.Join(versionDir(rcfg.BinDir, cockroachDBUpgrade.To), "cockroach")
+
+	for _, bench := range []struct{ name, readPercent string }{
+		{"kv0/nodes=3", "0"},
+		{"kv50/nodes=3", "50"},
+		{"kv95/nodes=3", "95"},
+	} {
 		args := append(rcfg.Args, []string{
-			"-bench", bench,
-			"-cockroachdb-bin", filepath.Join(rcfg.BinDir, "cockroach"),
+			"-bench", "mixedupgrade/" + bench.name,
+			"-cockroachdb-bin-old", oldBin,
+			"-cockroachdb-bin-new", newBin,
+			"-cockroachdb-version-old", cockroachDBUpgrade.From,
+			"-cockroachdb-version-new", cockroachDBUpgrade.To,
+			"-nodes", fmt.Sprint(cockroachDBUpgradeNodes),
+			"-concurrency", fmt.Sprint(cockroachDBUpgradeConcurrency),
+			"-warmup", cockroachDBUpgradeWarmup.String(),
+			"-duration", cockroachDBUpgradeDuration.String(),
 			"-tmp", rcfg.TmpDir,
 		}...)
+		args = append(args, clusterArgs()...)
 		if rcfg.Short {
 			args = append(args, "-short")
 		}
+		args = append(args, "--", "--read-percent", bench.readPercent)
 		cmd := exec.Command(
 			filepath.Join(rcfg.BinDir, "cockroachdb-bench"),
 			args...,
@@ -132,8 +597,6 @@ func (h CockroachDB) Run(cfg *common.Config, rcfg *common.RunConfig) error {
 		if err := cmd.Run(); err != nil {
 			return err
 		}
-		// Delete tmp because cockroachdb will have written something there and
-		// might attempt to reuse it. We don't want to reuse the same cluster.
 		if err := rmDirContents(rcfg.TmpDir); err != nil {
 			return err
 		}